@@ -0,0 +1,45 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package compiler
+
+import (
+	"testing"
+
+	"github.com/marapongo/mu/pkg/ast"
+	"github.com/marapongo/mu/pkg/compiler/backends"
+	"github.com/marapongo/mu/pkg/diag"
+	"github.com/marapongo/mu/pkg/workspace"
+)
+
+func TestNewAnonTarget_ReusesCachedName(t *testing.T) {
+	c := &compiler{opts: Options{
+		Diag:            &testSink{},
+		AnonTargetStore: workspace.NewInMemoryAnonTargetStore(),
+	}}
+	doc := &diag.Document{File: "/some/root/Mufile.yaml"}
+	stack := &ast.Stack{Name: "mystack"}
+	arch := backends.Arch{}
+
+	first := c.newAnonTarget(doc, stack, arch)
+	second := c.newAnonTarget(doc, stack, arch)
+
+	if first.Name != second.Name {
+		t.Errorf("expected the same anonymous target name to be reused, got %v and %v", first.Name, second.Name)
+	}
+}
+
+func TestNewAnonTarget_DifferentStacksGetDifferentNames(t *testing.T) {
+	c := &compiler{opts: Options{
+		Diag:            &testSink{},
+		AnonTargetStore: workspace.NewInMemoryAnonTargetStore(),
+	}}
+	doc := &diag.Document{File: "/some/root/Mufile.yaml"}
+	arch := backends.Arch{}
+
+	a := c.newAnonTarget(doc, &ast.Stack{Name: "stack-a"}, arch)
+	b := c.newAnonTarget(doc, &ast.Stack{Name: "stack-b"}, arch)
+
+	if a.Name == b.Name {
+		t.Error("expected different stacks to get different anonymous target names")
+	}
+}
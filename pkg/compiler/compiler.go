@@ -3,6 +3,8 @@
 package compiler
 
 import (
+	"path/filepath"
+
 	"github.com/golang/glog"
 	"github.com/satori/go.uuid"
 
@@ -156,10 +158,17 @@ func (c *compiler) discoverTargetArch(doc *diag.Document, stack *ast.Stack) (*as
 		if t, exists := stack.Targets[c.opts.Target]; exists {
 			target = &t
 		} else {
-			// If that didn't work, see if there's a clusters file we can consult.
-			// TODO: support Mucluster files.
-			c.Diag().Errorf(errors.CloudTargetNotFound.WithDocument(doc), c.opts.Target)
-			return target, arch, false
+			// If that didn't work, see if there's a cluster-wide Mucluster file we can consult.
+			clusterTargets, ok := c.discoverMuclusterTargets(filepath.Dir(doc.File))
+			if !ok {
+				return target, arch, false
+			}
+			if t, exists := clusterTargets[c.opts.Target]; exists {
+				target = &t
+			} else {
+				c.Diag().Errorf(errors.CloudTargetNotFound.WithDocument(doc), c.opts.Target)
+				return target, arch, false
+			}
 		}
 	}
 
@@ -181,7 +190,7 @@ func (c *compiler) discoverTargetArch(doc *diag.Document, stack *ast.Stack) (*as
 		}
 
 		// If we got here, generate an "anonymous" target, so that we at least have a name.
-		target = c.newAnonTarget(arch)
+		target = c.newAnonTarget(doc, stack, arch)
 	} else {
 		// If a target was found, go ahead and extract and validate the target architecture.
 		a, ok := c.getTargetArch(doc, target, arch)
@@ -194,12 +203,27 @@ func (c *compiler) discoverTargetArch(doc *diag.Document, stack *ast.Stack) (*as
 	return target, arch, true
 }
 
-// newAnonTarget creates an anonymous target for stacks that didn't declare one.
-func (c *compiler) newAnonTarget(arch backends.Arch) *ast.Target {
-	// TODO: ensure this is unique.
-	// TODO: we want to cache names somewhere (~/.mu/?) so that we can reuse temporary local stacks, etc.
+// newAnonTarget creates an anonymous target for stacks that didn't declare one, reusing a previously assigned name
+// for this stack, architecture, and workspace root if the AnonTargetStore has one cached.
+func (c *compiler) newAnonTarget(doc *diag.Document, stack *ast.Stack, arch backends.Arch) *ast.Target {
+	store := c.opts.AnonTargetStore
+	if store == nil {
+		store = workspace.NewAnonTargetStore()
+	}
+
+	root := filepath.Dir(doc.File)
+	key := workspace.AnonTargetKey(string(stack.Name), clouds.Names[arch.Cloud], schedulers.Names[arch.Scheduler], root)
+
+	name, ok := store.Get(key)
+	if !ok {
+		name = uuid.NewV4().String()
+		if err := store.Put(key, root, name); err != nil {
+			glog.Warningf("Could not persist anonymous target '%v' for future reuse: %v", name, err)
+		}
+	}
+
 	return &ast.Target{
-		Name:      uuid.NewV4().String(),
+		Name:      name,
 		Cloud:     clouds.Names[arch.Cloud],
 		Scheduler: schedulers.Names[arch.Scheduler],
 	}
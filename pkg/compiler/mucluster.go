@@ -0,0 +1,58 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package compiler
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/marapongo/mu/pkg/ast"
+	"github.com/marapongo/mu/pkg/diag"
+	"github.com/marapongo/mu/pkg/errors"
+	"github.com/marapongo/mu/pkg/workspace"
+)
+
+// discoverMuclusterTargets locates and parses the nearest Mucluster file relative to the input directory, returning
+// a map of named targets declared within it.  If no Mucluster file is found, a nil map is returned and ok is true,
+// since the absence of cluster-wide settings is not itself an error.
+func (c *compiler) discoverMuclusterTargets(inp string) (map[string]ast.Target, bool) {
+	path := workspace.DetectMuclusterFile(inp, c.Diag())
+	if path == "" {
+		return nil, true
+	}
+
+	doc, err := diag.ReadDocument(path)
+	if err != nil {
+		c.Diag().Errorf(errors.CouldNotReadMucluster.WithFile(path), err)
+		return nil, false
+	}
+
+	targets, err := parseMuclusterDocument(doc)
+	if err != nil {
+		c.Diag().Errorf(errors.CouldNotReadMucluster.WithDocument(doc), err)
+		return nil, false
+	}
+
+	return targets, true
+}
+
+// parseMuclusterDocument unmarshals a Mucluster document's body into a map of target name to target, dispatching
+// on the document's file extension the same way the Mufile loader does.
+func parseMuclusterDocument(doc *diag.Document) (map[string]ast.Target, error) {
+	var targets map[string]ast.Target
+
+	var err error
+	switch filepath.Ext(doc.File) {
+	case ".json":
+		err = json.Unmarshal(doc.Body, &targets)
+	default:
+		err = yaml.Unmarshal(doc.Body, &targets)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package compiler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marapongo/mu/pkg/ast"
+	"github.com/marapongo/mu/pkg/compiler/backends"
+	"github.com/marapongo/mu/pkg/compiler/backends/clouds"
+	"github.com/marapongo/mu/pkg/diag"
+	"github.com/marapongo/mu/pkg/workspace"
+)
+
+// testSink is a minimal diag.Sink that just records how many errors were reported; good enough to assert that
+// discoverTargetArch either succeeded or failed without standing up a full diagnostics pipeline.
+type testSink struct {
+	errs int
+}
+
+func (s *testSink) Errorf(d *diag.Diag, args ...interface{}) { s.errs++ }
+func (s *testSink) Warningf(d *diag.Diag, args ...interface{}) {}
+func (s *testSink) Errors() int { return s.errs }
+func (s *testSink) Warnings() int { return 0 }
+
+func newTestCompilerWithDoc(t *testing.T, muclusterYAML string) (*compiler, *diag.Document) {
+	dir, err := ioutil.TempDir("", "mucluster-compiler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if muclusterYAML != "" {
+		path := filepath.Join(dir, workspace.Mucluster+".yaml")
+		if err := ioutil.WriteFile(path, []byte(muclusterYAML), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &compiler{opts: Options{Diag: &testSink{}}}
+	doc := &diag.Document{File: filepath.Join(dir, "Mufile.yaml")}
+	return c, doc
+}
+
+func TestDiscoverTargetArch_StackTargetBeatsClusterTarget(t *testing.T) {
+	c, doc := newTestCompilerWithDoc(t, "foo:\n  cloud: gcp\n")
+	c.opts.Target = "foo"
+
+	stack := &ast.Stack{
+		Targets: map[string]ast.Target{
+			"foo": {Cloud: "aws"},
+		},
+	}
+
+	target, arch, ok := c.discoverTargetArch(doc, stack)
+	if !ok {
+		t.Fatalf("expected success, got %v error(s)", c.Diag().Errors())
+	}
+	if arch.Cloud != clouds.Values["aws"] {
+		t.Errorf("expected the stack's target (aws) to win over the cluster's (gcp), got %v", clouds.Names[arch.Cloud])
+	}
+	if target.Cloud != "aws" {
+		t.Errorf("expected resolved target to be the stack's, got %+v", target)
+	}
+}
+
+func TestDiscoverTargetArch_FallsBackToClusterTarget(t *testing.T) {
+	c, doc := newTestCompilerWithDoc(t, "bar:\n  cloud: gcp\n")
+	c.opts.Target = "bar"
+
+	stack := &ast.Stack{Targets: map[string]ast.Target{}}
+
+	target, arch, ok := c.discoverTargetArch(doc, stack)
+	if !ok {
+		t.Fatalf("expected success consulting the Mucluster file, got %v error(s)", c.Diag().Errors())
+	}
+	if arch.Cloud != clouds.Values["gcp"] {
+		t.Errorf("expected the cluster's target (gcp) to be used, got %v", clouds.Names[arch.Cloud])
+	}
+	if target == nil || target.Cloud != "gcp" {
+		t.Errorf("expected resolved target to come from the cluster file, got %+v", target)
+	}
+}
+
+func TestDiscoverTargetArch_ConflictsWithArchFlag(t *testing.T) {
+	c, doc := newTestCompilerWithDoc(t, "bar:\n  cloud: aws\n")
+	c.opts.Target = "bar"
+	c.opts.Arch = backends.Arch{Cloud: clouds.Values["gcp"]}
+
+	stack := &ast.Stack{Targets: map[string]ast.Target{}}
+
+	_, _, ok := c.discoverTargetArch(doc, stack)
+	if ok {
+		t.Fatal("expected a conflict between the -arch flag (gcp) and the Mucluster target (aws)")
+	}
+	if c.Diag().Errors() == 0 {
+		t.Error("expected a diagnostic to be reported for the conflicting target architecture")
+	}
+}
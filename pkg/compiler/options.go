@@ -0,0 +1,22 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package compiler
+
+import (
+	"github.com/marapongo/mu/pkg/compiler/backends"
+	"github.com/marapongo/mu/pkg/diag"
+	"github.com/marapongo/mu/pkg/workspace"
+)
+
+// Options controls the behavior of the Mu compiler.
+type Options struct {
+	Diag        diag.Sink     // a sink to use for all diagnostics.
+	Arch        backends.Arch // the target cloud/scheduler architecture, if any, requested on the command line.
+	Target      string        // the name of a specific target to use, if any, requested on the command line.
+	SkipCodegen bool          // true if code-generation should be skipped entirely (e.g. for a dry-run/validate).
+
+	// AnonTargetStore, if non-nil, is used to persist and recall the names generated for anonymous targets, so
+	// that repeated local builds of a stack with no declared target reuse the same name.  If nil, a default
+	// on-disk store rooted at ~/.mu is used.
+	AnonTargetStore workspace.AnonTargetStore
+}
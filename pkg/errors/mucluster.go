@@ -0,0 +1,20 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package errors
+
+import (
+	"github.com/marapongo/mu/pkg/diag"
+)
+
+// CouldNotReadMucluster indicates an I/O or parse error occurred while reading a cluster-wide Mucluster file.
+var CouldNotReadMucluster = &diag.Diag{
+	ID:      1016,
+	Message: "An IO or parsing error occurred while reading the Mucluster file: %v",
+}
+
+// CouldNotProbeMucluster indicates an unexpected error occurred while probing a candidate path for a Mucluster
+// file (as opposed to the file simply not existing there, which is expected while walking up the directory tree).
+var CouldNotProbeMucluster = &diag.Diag{
+	ID:      1017,
+	Message: "An error occurred while probing for a Mucluster file at '%v': %v",
+}
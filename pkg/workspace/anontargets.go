@@ -0,0 +1,232 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package workspace
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AnonTargetsFile is the name of the on-disk index used to remember the names assigned to anonymous targets.
+const AnonTargetsFile = "anon-targets.json"
+
+// AnonTargetStore persists the names generated for anonymous targets, keyed by a stable identity so that repeated
+// local builds reuse the same name rather than minting a fresh one on every invocation.
+type AnonTargetStore interface {
+	// Get returns the previously assigned name for the given key, if any.
+	Get(key string) (string, bool)
+	// Put records the given name for the given key, along with the workspace root it was generated from (so that
+	// stale entries can later be identified), persisting it for future lookups.
+	Put(key string, root string, name string) error
+}
+
+// AnonTargetKey computes a stable identity for an anonymous target, derived from the stack it belongs to, the
+// cloud/scheduler architecture it targets, and the root of the workspace it was built from.
+func AnonTargetKey(stack string, cloud string, scheduler string, root string) string {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	h := sha1.New()
+	h.Write([]byte(stack))
+	h.Write([]byte{0})
+	h.Write([]byte(cloud))
+	h.Write([]byte{0})
+	h.Write([]byte(scheduler))
+	h.Write([]byte{0})
+	h.Write([]byte(abs))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// anonTargetEntry is a single record in the on-disk index: the assigned target name, plus the workspace root it
+// was generated from, so that entries whose workspace has since been deleted can be identified and purged.
+type anonTargetEntry struct {
+	Name string `json:"name"`
+	Root string `json:"root"`
+}
+
+// fileAnonTargetStore is the default AnonTargetStore, backed by a JSON index underneath ~/.mu.
+type fileAnonTargetStore struct {
+	path string
+}
+
+// NewAnonTargetStore returns the default AnonTargetStore, backed by ~/.mu/anon-targets.json.
+func NewAnonTargetStore() AnonTargetStore {
+	return &fileAnonTargetStore{path: anonTargetsPath()}
+}
+
+// MuHome returns the path to the current user's Mu home directory (~/.mu), where local state such as the
+// anonymous target index is kept.
+func MuHome() string {
+	home := os.Getenv("HOME")
+	if home == "" {
+		home = "."
+	}
+	return filepath.Join(home, ".mu")
+}
+
+func anonTargetsPath() string {
+	return filepath.Join(MuHome(), AnonTargetsFile)
+}
+
+func (s *fileAnonTargetStore) load() (map[string]anonTargetEntry, error) {
+	b, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]anonTargetEntry), nil
+		}
+		return nil, err
+	}
+	entries := make(map[string]anonTargetEntry)
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *fileAnonTargetStore) Get(key string) (string, bool) {
+	entries, err := s.load()
+	if err != nil {
+		return "", false
+	}
+	entry, ok := entries[key]
+	return entry.Name, ok
+}
+
+func (s *fileAnonTargetStore) Put(key string, root string, name string) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[key] = anonTargetEntry{Name: name, Root: root}
+	return s.write(entries)
+}
+
+// lockPath is the sibling file used to mediate exclusive access to the index, since two concurrent builds (e.g.
+// parallel CI jobs sharing ~/.mu) reading, modifying, and writing the index back can otherwise race and silently
+// clobber one another's freshly cached name.
+func (s *fileAnonTargetStore) lockPath() string {
+	return s.path + ".lock"
+}
+
+// lock acquires an exclusive, advisory lock on the index by creating its sibling lock file, retrying with a short
+// backoff while another process holds it.  The returned function releases the lock and must always be called.
+func (s *fileAnonTargetStore) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return nil, err
+	}
+
+	lockPath := s.lockPath()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the lock on '%v'", s.path)
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+}
+
+// write atomically persists the given entries, writing to a temp file alongside the index and renaming it into
+// place, so that a crash mid-write can never leave the index truncated or corrupt.
+func (s *fileAnonTargetStore) write(entries map[string]anonTargetEntry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), "anon-targets-")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, s.path)
+}
+
+// memAnonTargetStore is an in-memory AnonTargetStore, useful for tests that want to exercise code depending on
+// Options.AnonTargetStore without touching the real ~/.mu directory.
+type memAnonTargetStore struct {
+	entries map[string]string
+}
+
+// NewInMemoryAnonTargetStore returns an AnonTargetStore backed by a plain in-memory map, for use in tests.
+func NewInMemoryAnonTargetStore() AnonTargetStore {
+	return &memAnonTargetStore{entries: make(map[string]string)}
+}
+
+func (s *memAnonTargetStore) Get(key string) (string, bool) {
+	name, ok := s.entries[key]
+	return name, ok
+}
+
+func (s *memAnonTargetStore) Put(key string, root string, name string) error {
+	s.entries[key] = name
+	return nil
+}
+
+// CleanAnonTargets purges entries from the anonymous target index whose workspace root no longer exists on disk,
+// and returns the number of entries removed.
+// TODO: wire this up to a `mu clean` command; no cmd/CLI package exists in this part of the tree yet, so this is
+// currently unreachable outside of tests.
+func CleanAnonTargets() (int, error) {
+	s := &fileAnonTargetStore{path: anonTargetsPath()}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for key, entry := range entries {
+		if _, err := os.Stat(entry.Root); err != nil && os.IsNotExist(err) {
+			delete(entries, key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		if err := s.write(entries); err != nil {
+			return 0, err
+		}
+	}
+	return removed, nil
+}
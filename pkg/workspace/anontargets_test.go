@@ -0,0 +1,139 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnonTargetKey_StableAndDistinct(t *testing.T) {
+	k1 := AnonTargetKey("stack", "aws", "ecs", "/tmp/root")
+	k2 := AnonTargetKey("stack", "aws", "ecs", "/tmp/root")
+	if k1 != k2 {
+		t.Errorf("expected the same inputs to produce the same key, got %v and %v", k1, k2)
+	}
+
+	if k3 := AnonTargetKey("stack", "gcp", "ecs", "/tmp/root"); k3 == k1 {
+		t.Error("expected a different cloud to produce a different key")
+	}
+	if k4 := AnonTargetKey("other", "aws", "ecs", "/tmp/root"); k4 == k1 {
+		t.Error("expected a different stack name to produce a different key")
+	}
+}
+
+func withTempMuHome(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "muhome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+	t.Cleanup(func() {
+		os.Setenv("HOME", old)
+		os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func TestFileAnonTargetStore_GetMissReturnsFalse(t *testing.T) {
+	withTempMuHome(t)
+	s := NewAnonTargetStore()
+
+	if _, ok := s.Get("nope"); ok {
+		t.Error("expected a miss for a key that was never put")
+	}
+}
+
+func TestFileAnonTargetStore_PutThenGetPersistsAcrossInstances(t *testing.T) {
+	withTempMuHome(t)
+
+	if err := NewAnonTargetStore().Put("key1", "/some/root", "target-name"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A freshly constructed store should see the same entry, proving it round-trips through disk.
+	name, ok := NewAnonTargetStore().Get("key1")
+	if !ok {
+		t.Fatal("expected the entry written by one store instance to be visible to another")
+	}
+	if name != "target-name" {
+		t.Errorf("expected 'target-name', got %v", name)
+	}
+}
+
+func TestFileAnonTargetStore_WriteIsAtomic(t *testing.T) {
+	dir := withTempMuHome(t)
+	s := NewAnonTargetStore()
+
+	if err := s.Put("key1", "/root1", "name1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// After a successful Put, no leftover temp files should remain alongside the index.
+	entries, err := ioutil.ReadDir(filepath.Join(dir, ".mu"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != AnonTargetsFile {
+		t.Errorf("expected only %v in the Mu home directory, got %v", AnonTargetsFile, entries)
+	}
+}
+
+func TestCleanAnonTargets_PurgesEntriesWithMissingRoots(t *testing.T) {
+	withTempMuHome(t)
+
+	liveRoot, err := ioutil.TempDir("", "live-root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(liveRoot)
+
+	staleRoot, err := ioutil.TempDir("", "stale-root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(staleRoot) // delete it immediately so it's "stale" for the purposes of this test.
+
+	s := NewAnonTargetStore()
+	if err := s.Put("live", liveRoot, "live-name"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("stale", staleRoot, "stale-name"); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := CleanAnonTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Errorf("expected exactly one stale entry to be purged, got %v", removed)
+	}
+
+	if _, ok := s.Get("live"); !ok {
+		t.Error("expected the entry with a live root to survive")
+	}
+	if _, ok := s.Get("stale"); ok {
+		t.Error("expected the entry with a missing root to be purged")
+	}
+}
+
+func TestInMemoryAnonTargetStore_GetPut(t *testing.T) {
+	s := NewInMemoryAnonTargetStore()
+
+	if _, ok := s.Get("k"); ok {
+		t.Error("expected a miss on an empty store")
+	}
+
+	if err := s.Put("k", "/root", "name"); err != nil {
+		t.Fatal(err)
+	}
+
+	name, ok := s.Get("k")
+	if !ok || name != "name" {
+		t.Errorf("expected a hit with 'name', got %v, %v", name, ok)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/marapongo/mu/pkg/diag"
+	"github.com/marapongo/mu/pkg/errors"
+)
+
+// Mucluster is the base name of the file used to express cluster-wide target and architecture settings.
+const Mucluster = "Mucluster"
+
+// MuclusterExts is the list of extensions recognized for Mucluster files, in order of search preference.
+var MuclusterExts = []string{".yaml", ".json"}
+
+// DetectMuclusterFile locates the nearest Mucluster file, starting in the given directory and walking upwards
+// through its ancestors until either one is found or we reach the root of the filesystem.  This mirrors the way
+// DetectMufile discovers a package's Mufile, except that Mucluster files are meant to apply to every package
+// rooted beneath the directory in which they are found.  If no such file is found, the empty string is returned.
+func DetectMuclusterFile(from string, d diag.Sink) string {
+	dir := from
+	for {
+		for _, ext := range MuclusterExts {
+			path := filepath.Join(dir, Mucluster+ext)
+			info, err := os.Stat(path)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					d.Warningf(errors.CouldNotProbeMucluster.WithFile(path), err)
+				}
+				continue
+			}
+			if !info.IsDir() {
+				return path
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			// We've reached the root of the filesystem without finding a Mucluster file.
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2016 Marapongo, Inc. All rights reserved.
+
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marapongo/mu/pkg/diag"
+)
+
+// nopSink is a minimal diag.Sink that just counts errors and warnings; it's good enough for exercising the
+// directory-walk logic in DetectMuclusterFile without pulling in a real diagnostics pipeline.
+type nopSink struct {
+	warnings int
+}
+
+func (s *nopSink) Errorf(d *diag.Diag, args ...interface{}) {}
+func (s *nopSink) Warningf(d *diag.Diag, args ...interface{}) { s.warnings++ }
+func (s *nopSink) Errors() int { return 0 }
+func (s *nopSink) Warnings() int { return s.warnings }
+
+func TestDetectMuclusterFile_FindsInStartDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mucluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, Mucluster+".yaml")
+	if err := ioutil.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := DetectMuclusterFile(dir, &nopSink{}); got != path {
+		t.Errorf("expected to find %v, got %v", path, got)
+	}
+}
+
+func TestDetectMuclusterFile_WalksUpAncestors(t *testing.T) {
+	root, err := ioutil.TempDir("", "mucluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	path := filepath.Join(root, Mucluster+".json")
+	if err := ioutil.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	nested := filepath.Join(root, "a", "b", "c")
+	if err := os.MkdirAll(nested, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := DetectMuclusterFile(nested, &nopSink{}); got != path {
+		t.Errorf("expected to find %v starting from %v, got %v", path, nested, got)
+	}
+}
+
+func TestDetectMuclusterFile_NotFound(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mucluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if got := DetectMuclusterFile(dir, &nopSink{}); got != "" {
+		t.Errorf("expected no Mucluster file to be found, got %v", got)
+	}
+}
+
+func TestDetectMuclusterFile_PrefersYAMLOverJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mucluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	yamlPath := filepath.Join(dir, Mucluster+".yaml")
+	jsonPath := filepath.Join(dir, Mucluster+".json")
+	if err := ioutil.WriteFile(yamlPath, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(jsonPath, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := DetectMuclusterFile(dir, &nopSink{}); got != yamlPath {
+		t.Errorf("expected yaml to be preferred (%v), got %v", yamlPath, got)
+	}
+}